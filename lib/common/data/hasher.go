@@ -0,0 +1,78 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"errors"
+	"hash"
+)
+
+// Hasher is a streaming, resumable SHA-256 hasher implementing hash.Hash (and
+// thus io.Writer). Unlike HashData and HashReader, which require the full
+// payload up front, Hasher lets callers feed it a RouterInfo, Garlic message,
+// or other payload incrementally as it arrives off the wire.
+type Hasher struct {
+	sha hash.Hash
+}
+
+// NewHasher returns a ready-to-use *Hasher with no data written yet.
+func NewHasher() *Hasher {
+	return &Hasher{sha: sha256.New()}
+}
+
+// Write implements io.Writer, adding p to the running hash. It never returns
+// an error.
+func (h *Hasher) Write(p []byte) (int, error) {
+	return h.sha.Write(p)
+}
+
+// Sum appends the current hash to b and returns the resulting slice, without
+// changing the underlying hash state.
+func (h *Hasher) Sum(b []byte) []byte {
+	return h.sha.Sum(b)
+}
+
+// Reset discards all data written so far.
+func (h *Hasher) Reset() {
+	h.sha.Reset()
+}
+
+// Size returns the number of bytes Sum will append: 32.
+func (h *Hasher) Size() int {
+	return h.sha.Size()
+}
+
+// BlockSize returns the hash's underlying block size.
+func (h *Hasher) BlockSize() int {
+	return h.sha.BlockSize()
+}
+
+// I2PHash returns the current hash state as this package's native Hash type.
+func (h *Hasher) I2PHash() (out Hash) {
+	d := h.sha.Sum(nil)
+	copy(out[:], d)
+	return
+}
+
+// MarshalBinary snapshots the Hasher's partial state so it can be persisted
+// and resumed later, e.g. across a process restart during long-lived
+// tunnel-build verification.
+func (h *Hasher) MarshalBinary() ([]byte, error) {
+	marshaler, ok := h.sha.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, errors.New("data: underlying hash does not support binary marshaling")
+	}
+	return marshaler.MarshalBinary()
+}
+
+// UnmarshalBinary restores a Hasher snapshot produced by MarshalBinary.
+func (h *Hasher) UnmarshalBinary(state []byte) error {
+	if h.sha == nil {
+		h.sha = sha256.New()
+	}
+	unmarshaler, ok := h.sha.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return errors.New("data: underlying hash does not support binary unmarshaling")
+	}
+	return unmarshaler.UnmarshalBinary(state)
+}
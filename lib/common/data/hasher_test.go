@@ -0,0 +1,134 @@
+package data
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+)
+
+func TestHasherMatchesHashData(t *testing.T) {
+	payload := randomBytes(t, 4096)
+
+	h := NewHasher()
+	if _, err := h.Write(payload); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	want := HashData(payload)
+	if got := h.I2PHash(); got != want {
+		t.Errorf("I2PHash() = %x, want %x", got, want)
+	}
+}
+
+func TestHasherStreamingMatchesSingleWrite(t *testing.T) {
+	payload := randomBytes(t, 4096)
+
+	streamed := NewHasher()
+	for i := 0; i < len(payload); i += 17 {
+		end := i + 17
+		if end > len(payload) {
+			end = len(payload)
+		}
+		streamed.Write(payload[i:end])
+	}
+
+	whole := NewHasher()
+	whole.Write(payload)
+
+	if streamed.I2PHash() != whole.I2PHash() {
+		t.Errorf("streamed hash %x != whole hash %x", streamed.I2PHash(), whole.I2PHash())
+	}
+}
+
+func TestHasherResetAndSize(t *testing.T) {
+	h := NewHasher()
+	if h.Size() != 32 {
+		t.Errorf("Size() = %d, want 32", h.Size())
+	}
+	h.Write([]byte("some data"))
+	h.Reset()
+	if got, want := h.I2PHash(), HashData(nil); got != want {
+		t.Errorf("I2PHash() after Reset = %x, want %x", got, want)
+	}
+}
+
+func TestHasherMarshalBinaryRoundTrip(t *testing.T) {
+	payload := randomBytes(t, 4096)
+
+	h := NewHasher()
+	h.Write(payload[:2048])
+
+	state, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	resumed := NewHasher()
+	if err := resumed.UnmarshalBinary(state); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	resumed.Write(payload[2048:])
+
+	h.Write(payload[2048:])
+
+	if resumed.I2PHash() != h.I2PHash() {
+		t.Errorf("resumed hash %x != continuous hash %x", resumed.I2PHash(), h.I2PHash())
+	}
+}
+
+func BenchmarkHasherWrite1MiB(b *testing.B) {
+	benchmarkHasherWrite(b, 1<<20)
+}
+
+func BenchmarkHasherWrite16MiB(b *testing.B) {
+	benchmarkHasherWrite(b, 16<<20)
+}
+
+func BenchmarkHashReader1MiB(b *testing.B) {
+	benchmarkHashReader(b, 1<<20)
+}
+
+func BenchmarkHashReader16MiB(b *testing.B) {
+	benchmarkHashReader(b, 16<<20)
+}
+
+func benchmarkHasherWrite(b *testing.B, size int) {
+	payload := randomBytesB(b, size)
+	b.ResetTimer()
+	b.SetBytes(int64(size))
+	for i := 0; i < b.N; i++ {
+		h := NewHasher()
+		h.Write(payload)
+		h.Sum(nil)
+	}
+}
+
+func benchmarkHashReader(b *testing.B, size int) {
+	payload := randomBytesB(b, size)
+	b.ResetTimer()
+	b.SetBytes(int64(size))
+	for i := 0; i < b.N; i++ {
+		if _, err := HashReader(bytes.NewReader(payload)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func randomBytes(t *testing.T, size int) []byte {
+	t.Helper()
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func randomBytesB(b *testing.B, size int) []byte {
+	b.Helper()
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		b.Fatal(err)
+	}
+	return buf
+}
@@ -0,0 +1,168 @@
+package router_address
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	. "github.com/go-i2p/go-i2p/lib/common/data"
+)
+
+// Bounds on the transport_style String's content, per the RouterAddress
+// spec's "1-256 bytes" describing the whole wire-format String (a 1-byte
+// length prefix followed by up to 255 bytes of data).
+const (
+	MIN_TRANSPORT_STYLE_LENGTH = 0
+	MAX_TRANSPORT_STYLE_LENGTH = 255
+)
+
+var (
+	// ErrCostOutOfRange is returned by SetCost when the cost is outside 0-255.
+	ErrCostOutOfRange = errors.New("router_address: cost must be between 0 and 255")
+	// ErrTransportStyleLength is returned by SetTransportStyle when the style
+	// is longer than 255 bytes and cannot be represented by the I2PString's
+	// 1-byte length prefix.
+	ErrTransportStyleLength = errors.New("router_address: transport style must be 0-255 bytes")
+	// ErrTransportStyleNotSet is returned by Build when the transport style is
+	// empty: SetTransportStyle was never called, or was called with "".
+	ErrTransportStyleNotSet = errors.New("router_address: transport style is required")
+)
+
+// RouterAddressBuilder constructs a RouterAddress from Go values instead of
+// parsing one off the wire with ReadRouterAddress. Build validates each
+// field against the spec and produces a RouterAddress whose Bytes()
+// round-trips through ReadRouterAddress.
+type RouterAddressBuilder struct {
+	cost            int
+	expiration      time.Time
+	transport_style string
+	options         map[string]string
+	err             error
+}
+
+// NewRouterAddressBuilder returns an empty *RouterAddressBuilder ready for
+// configuration.
+func NewRouterAddressBuilder() *RouterAddressBuilder {
+	return &RouterAddressBuilder{
+		options: make(map[string]string),
+	}
+}
+
+// SetCost sets the relative cost of using this address, where 0 is free and
+// 255 is expensive.
+func (b *RouterAddressBuilder) SetCost(cost int) *RouterAddressBuilder {
+	if cost < 0 || cost > 255 {
+		b.err = ErrCostOutOfRange
+		return b
+	}
+	b.cost = cost
+	return b
+}
+
+// SetExpiration sets the expiration Date for this address. The zero
+// time.Time produces a null Date, meaning the address never expires.
+func (b *RouterAddressBuilder) SetExpiration(expiration time.Time) *RouterAddressBuilder {
+	b.expiration = expiration
+	return b
+}
+
+// SetTransportStyle sets the transport protocol name, e.g. "NTCP2" or "SSU2".
+func (b *RouterAddressBuilder) SetTransportStyle(style string) *RouterAddressBuilder {
+	if len(style) < MIN_TRANSPORT_STYLE_LENGTH || len(style) > MAX_TRANSPORT_STYLE_LENGTH {
+		b.err = ErrTransportStyleLength
+		return b
+	}
+	b.transport_style = style
+	return b
+}
+
+// AddOption adds a key/value pair to the address' transport options
+// mapping. Build sorts the mapping into canonical I2P order regardless of
+// insertion order.
+func (b *RouterAddressBuilder) AddOption(key, value string) *RouterAddressBuilder {
+	b.options[key] = value
+	return b
+}
+
+// Build validates the accumulated fields and produces a *RouterAddress whose
+// Bytes() round-trips through ReadRouterAddress.
+func (b *RouterAddressBuilder) Build() (*RouterAddress, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.transport_style == "" {
+		return nil, ErrTransportStyleNotSet
+	}
+
+	cost, err := NewIntegerFromInt(b.cost, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	date := DateFromTime(b.expiration)
+
+	transportStyle, err := ToI2PString(b.transport_style)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := GoMapToMapping(b.options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RouterAddress{
+		cost:            cost,
+		expiration:      date,
+		transport_style: &transportStyle,
+		options:         mapping,
+	}, nil
+}
+
+// defaultRouterAddressCaps is used for the "caps" option by NewNTCP2Address
+// and NewSSU2Address when the caller doesn't supply one. "R" marks the
+// address reachable, the common case for a directly dialable address.
+const defaultRouterAddressCaps = "R"
+
+// NewNTCP2Address builds a RouterAddress for the NTCP2 transport, populating
+// the "host", "port", "s" (static key), "i" (IV), "v" (version), and "caps"
+// option keys required to establish an NTCP2 connection. caps is optional;
+// when omitted it defaults to defaultRouterAddressCaps.
+func NewNTCP2Address(host string, port int, staticKey, iv []byte, cost int, caps ...string) (*RouterAddress, error) {
+	return NewRouterAddressBuilder().
+		SetCost(cost).
+		SetTransportStyle("NTCP2").
+		AddOption("host", host).
+		AddOption("port", strconv.Itoa(port)).
+		AddOption("s", Base64Encode(staticKey)).
+		AddOption("i", Base64Encode(iv)).
+		AddOption("v", "2").
+		AddOption("caps", routerAddressCaps(caps)).
+		Build()
+}
+
+// NewSSU2Address builds a RouterAddress for the SSU2 transport, populating
+// the "host", "port", "s" (static key), "i" (intro key), "v" (version), and
+// "caps" option keys required to establish an SSU2 session. caps is
+// optional; when omitted it defaults to defaultRouterAddressCaps.
+func NewSSU2Address(host string, port int, staticKey, introKey []byte, cost int, caps ...string) (*RouterAddress, error) {
+	return NewRouterAddressBuilder().
+		SetCost(cost).
+		SetTransportStyle("SSU2").
+		AddOption("host", host).
+		AddOption("port", strconv.Itoa(port)).
+		AddOption("s", Base64Encode(staticKey)).
+		AddOption("i", Base64Encode(introKey)).
+		AddOption("v", "2").
+		AddOption("caps", routerAddressCaps(caps)).
+		Build()
+}
+
+// routerAddressCaps returns caps[0] if it is present and non-empty, or
+// defaultRouterAddressCaps otherwise. Later elements of caps are ignored.
+func routerAddressCaps(caps []string) string {
+	if len(caps) > 0 && caps[0] != "" {
+		return caps[0]
+	}
+	return defaultRouterAddressCaps
+}
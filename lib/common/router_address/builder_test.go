@@ -0,0 +1,121 @@
+package router_address
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRouterAddressBuilderRoundTrip(t *testing.T) {
+	addr, err := NewRouterAddressBuilder().
+		SetCost(42).
+		SetTransportStyle("NTCP2").
+		SetExpiration(time.Time{}).
+		AddOption("host", "example.i2p").
+		AddOption("port", "12345").
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	parsed, remainder, err := ReadRouterAddress(addr.Bytes())
+	if err != nil {
+		t.Fatalf("ReadRouterAddress: %v", err)
+	}
+	if len(remainder) != 0 {
+		t.Errorf("ReadRouterAddress left %d bytes of remainder, want 0", len(remainder))
+	}
+	if parsed.Cost() != addr.Cost() {
+		t.Errorf("Cost() = %d, want %d", parsed.Cost(), addr.Cost())
+	}
+	if parsed.Bytes() == nil || string(parsed.Bytes()) != string(addr.Bytes()) {
+		t.Errorf("Bytes() did not round-trip: got %x, want %x", parsed.Bytes(), addr.Bytes())
+	}
+}
+
+// TestRouterAddressBuilderCanonicalOptionOrder verifies that AddOption's
+// insertion order has no effect on the serialized mapping: GoMapToMapping
+// must sort options into canonical I2P order (by key bytes) regardless of
+// how the caller added them. Options are deliberately added out of
+// alphabetical order here so a builder that forgot to sort would fail.
+func TestRouterAddressBuilderCanonicalOptionOrder(t *testing.T) {
+	reverseOrder, err := NewRouterAddressBuilder().
+		SetCost(0).
+		SetTransportStyle("NTCP2").
+		AddOption("v", "2").
+		AddOption("caps", "R").
+		AddOption("host", "example.i2p").
+		Build()
+	if err != nil {
+		t.Fatalf("Build (reverse order): %v", err)
+	}
+
+	forwardOrder, err := NewRouterAddressBuilder().
+		SetCost(0).
+		SetTransportStyle("NTCP2").
+		AddOption("caps", "R").
+		AddOption("host", "example.i2p").
+		AddOption("v", "2").
+		Build()
+	if err != nil {
+		t.Fatalf("Build (forward order): %v", err)
+	}
+
+	if string(reverseOrder.Bytes()) != string(forwardOrder.Bytes()) {
+		t.Fatalf("insertion order changed serialized bytes: reverse = %x, forward = %x",
+			reverseOrder.Bytes(), forwardOrder.Bytes())
+	}
+
+	var keys []string
+	for _, kv := range reverseOrder.Options().Values() {
+		key, err := kv[0].Data()
+		if err != nil {
+			t.Fatalf("option key Data(): %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Errorf("options not in canonical sorted order: %v", keys)
+	}
+}
+
+// FuzzRouterAddressRoundTrip builds a RouterAddress from fuzzer-controlled
+// values and asserts that serializing it with Bytes() and re-parsing with
+// ReadRouterAddress reproduces the same bytes, guarding the Bytes()
+// options-mapping fix against regressions.
+func FuzzRouterAddressRoundTrip(f *testing.F) {
+	f.Add(0, "NTCP2", "host", "127.0.0.1")
+	f.Add(255, "SSU2", "caps", "XO")
+	f.Add(128, "NTCP2", "", "")
+
+	f.Fuzz(func(t *testing.T, cost int, style, optKey, optValue string) {
+		if cost < 0 || cost > 255 {
+			t.Skip("cost out of range")
+		}
+		if len(style) < MIN_TRANSPORT_STYLE_LENGTH || len(style) > MAX_TRANSPORT_STYLE_LENGTH {
+			t.Skip("transport style out of range")
+		}
+
+		b := NewRouterAddressBuilder().
+			SetCost(cost).
+			SetTransportStyle(style)
+		if optKey != "" {
+			b.AddOption(optKey, optValue)
+		}
+
+		addr, err := b.Build()
+		if err != nil {
+			t.Skipf("Build: %v", err)
+		}
+
+		want := addr.Bytes()
+		parsed, _, err := ReadRouterAddress(want)
+		if err != nil {
+			t.Fatalf("ReadRouterAddress: %v", err)
+		}
+		got := parsed.Bytes()
+		if string(got) != string(want) {
+			t.Errorf("round trip mismatch: Build->Bytes = %x, Read->Bytes = %x", want, got)
+		}
+	})
+}
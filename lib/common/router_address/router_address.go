@@ -75,15 +75,12 @@ func (router_address RouterAddress) Bytes() []byte {
 	bytes := make([]byte, 0)
 	bytes = append(bytes, router_address.cost.Bytes()...)
 	bytes = append(bytes, router_address.expiration.Bytes()...)
-	strData, err := router_address.transport_style.Data()
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Error("RouterAddress.Bytes: error getting transport_style bytes")
-	} else {
-		bytes = append(bytes, strData...)
+	if router_address.transport_style != nil {
+		bytes = append(bytes, router_address.transport_style.Bytes()...)
+	}
+	if router_address.options != nil {
+		bytes = append(bytes, router_address.options.Bytes()...)
 	}
-	//bytes = append(bytes, router_address.options.Bytes()...)
 	return bytes
 }
 
@@ -143,7 +140,7 @@ func ReadRouterAddress(data []byte) (router_address RouterAddress, remainder []b
 		router_address.parserErr = err
 		return
 	}
-	cost, remainder, err := NewInteger([]byte{data[0]}, 1)
+	cost, remainder, err := NewInteger(data, 1)
 	router_address.cost = cost
 	if err != nil {
 		log.WithFields(log.Fields{
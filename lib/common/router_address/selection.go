@@ -0,0 +1,262 @@
+package router_address
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoUsableAddress is returned by SelectRouterAddress when addrs is empty
+// or none of its addresses parsed successfully, so there is nothing to
+// choose from.
+var ErrNoUsableAddress = errors.New("router_address: no usable address found")
+
+// Reachability describes which IP families the local router can accept
+// introductions on. It is compared against a candidate address' "i"
+// (introducer) option when ranking addresses.
+type Reachability int
+
+const (
+	ReachableIPv4 Reachability = iota
+	ReachableIPv6
+	ReachableBoth
+)
+
+// Preferences configures SelectRouterAddress and SortRouterAddresses. It
+// plays the same role that the destination and source IP properties play in
+// Go's net/addrselect.go: a bundle of caller-known facts the rule chain
+// consults when two addresses would otherwise tie.
+type Preferences struct {
+	// EnabledTransports lists the transport styles (e.g. "NTCP2", "SSU2")
+	// the caller is actually able to dial.
+	EnabledTransports map[string]bool
+
+	// TransportPriority orders transport styles from most to least
+	// preferred. Transports absent from the list rank below any that are
+	// listed.
+	TransportPriority []string
+
+	// Reachability describes which IP families the caller can be
+	// introduced on.
+	Reachability Reachability
+
+	// Now is the reference time used to evaluate address expiration. The
+	// zero value means time.Now() is used.
+	Now time.Time
+}
+
+func (p Preferences) now() time.Time {
+	if p.Now.IsZero() {
+		return time.Now()
+	}
+	return p.Now
+}
+
+// addressLess is one link in the RouterAddress selection rule chain. ok is
+// false when the rule cannot distinguish a from b, in which case the next
+// rule in the chain is consulted.
+type addressLess func(a, b RouterAddress, prefs Preferences) (less bool, ok bool)
+
+// SelectionRules is the ordered rule chain used by SortRouterAddresses and
+// SelectRouterAddress. Callers may replace or extend it to change ordering
+// behavior globally, mirroring the policy table net/addrselect.go builds for
+// RFC 3484 destination address selection.
+var SelectionRules = []addressLess{
+	ruleNotExpired,
+	ruleEnabledTransport,
+	ruleLowerCost,
+	ruleTransportPriority,
+	ruleIntroducerReachability,
+}
+
+// ruleNotExpired prefers addresses that have not expired. An address with a
+// null Expiration never expires.
+func ruleNotExpired(a, b RouterAddress, prefs Preferences) (bool, bool) {
+	aExpired := addressExpired(a, prefs)
+	bExpired := addressExpired(b, prefs)
+	if aExpired == bExpired {
+		return false, false
+	}
+	return !aExpired, true
+}
+
+func addressExpired(addr RouterAddress, prefs Preferences) bool {
+	if addr.expiration == nil {
+		return false
+	}
+	exp := addr.expiration.Time()
+	if exp.IsZero() {
+		return false
+	}
+	return exp.Before(prefs.now())
+}
+
+// ruleEnabledTransport prefers addresses using a transport the caller marked
+// usable in Preferences.EnabledTransports.
+func ruleEnabledTransport(a, b RouterAddress, prefs Preferences) (bool, bool) {
+	if len(prefs.EnabledTransports) == 0 {
+		return false, false
+	}
+	aOk := prefs.EnabledTransports[transportStyleString(a)]
+	bOk := prefs.EnabledTransports[transportStyleString(b)]
+	if aOk == bOk {
+		return false, false
+	}
+	return aOk, true
+}
+
+// ruleLowerCost prefers the cheaper address to dial.
+func ruleLowerCost(a, b RouterAddress, prefs Preferences) (bool, bool) {
+	aCost, bCost := costOf(a), costOf(b)
+	if aCost == bCost {
+		return false, false
+	}
+	return aCost < bCost, true
+}
+
+// costOf returns addr's cost, or 255 (the most expensive valid cost) if addr
+// failed to parse a cost at all.
+func costOf(addr RouterAddress) int {
+	if addr.cost == nil {
+		return 255
+	}
+	return addr.cost.Int()
+}
+
+// ruleTransportPriority prefers transports that appear earlier in
+// Preferences.TransportPriority. Transports absent from the list rank last.
+func ruleTransportPriority(a, b RouterAddress, prefs Preferences) (bool, bool) {
+	aIdx := transportPriorityIndex(transportStyleString(a), prefs.TransportPriority)
+	bIdx := transportPriorityIndex(transportStyleString(b), prefs.TransportPriority)
+	if aIdx == bIdx {
+		return false, false
+	}
+	return aIdx < bIdx, true
+}
+
+func transportPriorityIndex(style string, priority []string) int {
+	for i, s := range priority {
+		if s == style {
+			return i
+		}
+	}
+	return len(priority)
+}
+
+// ruleIntroducerReachability prefers addresses whose "i" option indicates an
+// introducer compatible with the caller's reachability.
+func ruleIntroducerReachability(a, b RouterAddress, prefs Preferences) (bool, bool) {
+	aMatch := introducerMatches(a, prefs.Reachability)
+	bMatch := introducerMatches(b, prefs.Reachability)
+	if aMatch == bMatch {
+		return false, false
+	}
+	return aMatch, true
+}
+
+// introducerMatches reports whether addr carries an "i" option compatible
+// with reach. IPv6 introducer addresses contain a colon; anything else is
+// treated as IPv4.
+func introducerMatches(addr RouterAddress, reach Reachability) bool {
+	if addr.options == nil {
+		return false
+	}
+	for _, kv := range addr.options.Values() {
+		key, err := kv[0].Data()
+		if err != nil || key != "i" {
+			continue
+		}
+		value, err := kv[1].Data()
+		if err != nil {
+			continue
+		}
+		switch reach {
+		case ReachableIPv4:
+			return !strings.Contains(value, ":")
+		case ReachableIPv6:
+			return strings.Contains(value, ":")
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func transportStyleString(addr RouterAddress) string {
+	if addr.transport_style == nil {
+		return ""
+	}
+	style, err := addr.transport_style.Data()
+	if err != nil {
+		return ""
+	}
+	return style
+}
+
+// SortRouterAddresses stable-sorts addrs in place from best to worst
+// candidate according to SelectionRules, falling back to comparing raw
+// transport-style bytes when every rule is indifferent.
+func SortRouterAddresses(addrs []RouterAddress, prefs Preferences) {
+	sort.SliceStable(addrs, func(i, j int) bool {
+		return addressLessThan(addrs[i], addrs[j], prefs)
+	})
+}
+
+func addressLessThan(a, b RouterAddress, prefs Preferences) bool {
+	for _, rule := range SelectionRules {
+		if less, ok := rule(a, b, prefs); ok {
+			return less
+		}
+	}
+	return transportStyleString(a) < transportStyleString(b)
+}
+
+// SelectRouterAddress picks the best RouterAddress to dial for a peer out of
+// addrs, applying the RFC 3484 style rule chain in SelectionRules. Addresses
+// that failed to parse (e.g. a malformed options Mapping) are dropped
+// outright. Of what remains, addresses that have expired are dropped unless
+// every candidate has expired, in which case the full set is kept rather
+// than reporting failure. ErrNoUsableAddress is returned when addrs is empty
+// or none of them parsed successfully.
+func SelectRouterAddress(addrs []RouterAddress, prefs Preferences) (*RouterAddress, error) {
+	usable := make([]RouterAddress, 0, len(addrs))
+	for _, a := range addrs {
+		if addressUsable(a) {
+			usable = append(usable, a)
+		}
+	}
+	if len(usable) == 0 {
+		return nil, ErrNoUsableAddress
+	}
+	candidates := dropExpired(usable, prefs)
+	SortRouterAddresses(candidates, prefs)
+	best := candidates[0]
+	return &best, nil
+}
+
+// addressUsable reports whether addr parsed successfully and has every
+// field the rule chain dereferences. It guards against panics on
+// RouterAddresses produced by ReadRouterAddress from malformed wire data.
+func addressUsable(addr RouterAddress) bool {
+	return addr.parserErr == nil &&
+		addr.cost != nil &&
+		addr.expiration != nil &&
+		addr.transport_style != nil &&
+		addr.options != nil
+}
+
+func dropExpired(addrs []RouterAddress, prefs Preferences) []RouterAddress {
+	filtered := make([]RouterAddress, 0, len(addrs))
+	for _, a := range addrs {
+		if !addressExpired(a, prefs) {
+			filtered = append(filtered, a)
+		}
+	}
+	if len(filtered) == 0 {
+		unexpired := make([]RouterAddress, len(addrs))
+		copy(unexpired, addrs)
+		return unexpired
+	}
+	return filtered
+}
@@ -0,0 +1,145 @@
+package router_address
+
+import (
+	"testing"
+	"time"
+)
+
+func mustBuildAddress(t *testing.T, style string, cost int, expiration time.Time, options map[string]string) RouterAddress {
+	t.Helper()
+	b := NewRouterAddressBuilder().
+		SetCost(cost).
+		SetTransportStyle(style).
+		SetExpiration(expiration)
+	for k, v := range options {
+		b.AddOption(k, v)
+	}
+	addr, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build(%q): %v", style, err)
+	}
+	return *addr
+}
+
+func TestSortRouterAddresses(t *testing.T) {
+	past := time.Unix(1000, 0)
+	future := time.Unix(1<<32, 0)
+	now := time.Unix(2000, 0)
+
+	tests := []struct {
+		name  string
+		addrs func(t *testing.T) []RouterAddress
+		prefs Preferences
+		want  string // expected transport style of the winning address
+	}{
+		{
+			name: "drops expired address unless all expired",
+			addrs: func(t *testing.T) []RouterAddress {
+				return []RouterAddress{
+					mustBuildAddress(t, "NTCP2", 10, past, nil),
+					mustBuildAddress(t, "SSU2", 20, future, nil),
+				}
+			},
+			prefs: Preferences{Now: now},
+			want:  "SSU2",
+		},
+		{
+			name: "keeps all candidates when every address is expired",
+			addrs: func(t *testing.T) []RouterAddress {
+				return []RouterAddress{
+					mustBuildAddress(t, "NTCP2", 20, past, nil),
+					mustBuildAddress(t, "SSU2", 10, past, nil),
+				}
+			},
+			prefs: Preferences{Now: now},
+			want:  "SSU2", // falls through to the cost rule instead of erroring
+		},
+		{
+			name: "prefers an enabled transport over a cheaper disabled one",
+			addrs: func(t *testing.T) []RouterAddress {
+				return []RouterAddress{
+					mustBuildAddress(t, "NTCP2", 0, time.Time{}, nil),
+					mustBuildAddress(t, "SSU2", 50, time.Time{}, nil),
+				}
+			},
+			prefs: Preferences{EnabledTransports: map[string]bool{"SSU2": true}, Now: now},
+			want:  "SSU2",
+		},
+		{
+			name: "prefers lower cost when transports tie",
+			addrs: func(t *testing.T) []RouterAddress {
+				return []RouterAddress{
+					mustBuildAddress(t, "NTCP2", 50, time.Time{}, nil),
+					mustBuildAddress(t, "SSU2", 10, time.Time{}, nil),
+				}
+			},
+			prefs: Preferences{Now: now},
+			want:  "SSU2",
+		},
+		{
+			name: "prefers transport priority order when cost ties",
+			addrs: func(t *testing.T) []RouterAddress {
+				return []RouterAddress{
+					mustBuildAddress(t, "NTCP2", 10, time.Time{}, nil),
+					mustBuildAddress(t, "SSU2", 10, time.Time{}, nil),
+				}
+			},
+			prefs: Preferences{TransportPriority: []string{"SSU2", "NTCP2"}, Now: now},
+			want:  "SSU2",
+		},
+		{
+			name: "prefers introducer reachability match when everything else ties",
+			addrs: func(t *testing.T) []RouterAddress {
+				return []RouterAddress{
+					mustBuildAddress(t, "NTCP2", 10, time.Time{}, map[string]string{"i": "10.0.0.1"}),
+					mustBuildAddress(t, "SSU2", 10, time.Time{}, map[string]string{"i": "::1"}),
+				}
+			},
+			prefs: Preferences{Reachability: ReachableIPv6, Now: now},
+			want:  "SSU2",
+		},
+		{
+			name: "falls back to transport-style bytes when every rule ties",
+			addrs: func(t *testing.T) []RouterAddress {
+				return []RouterAddress{
+					mustBuildAddress(t, "SSU2", 10, time.Time{}, nil),
+					mustBuildAddress(t, "NTCP2", 10, time.Time{}, nil),
+				}
+			},
+			prefs: Preferences{Now: now},
+			want:  "NTCP2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addrs := tt.addrs(t)
+			got, err := SelectRouterAddress(addrs, tt.prefs)
+			if err != nil {
+				t.Fatalf("SelectRouterAddress: %v", err)
+			}
+			if style := transportStyleString(*got); style != tt.want {
+				t.Errorf("SelectRouterAddress() = %q, want %q", style, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectRouterAddressNoAddresses(t *testing.T) {
+	if _, err := SelectRouterAddress(nil, Preferences{}); err != ErrNoUsableAddress {
+		t.Errorf("SelectRouterAddress(nil) error = %v, want %v", err, ErrNoUsableAddress)
+	}
+}
+
+func TestSelectRouterAddressSkipsMalformed(t *testing.T) {
+	malformed := RouterAddress{parserErr: ErrNoUsableAddress}
+	good := mustBuildAddress(t, "NTCP2", 10, time.Time{}, nil)
+
+	got, err := SelectRouterAddress([]RouterAddress{malformed, good}, Preferences{})
+	if err != nil {
+		t.Fatalf("SelectRouterAddress: %v", err)
+	}
+	if style := transportStyleString(*got); style != "NTCP2" {
+		t.Errorf("SelectRouterAddress() = %q, want NTCP2", style)
+	}
+}